@@ -0,0 +1,164 @@
+package bgp
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/czerwonk/junos_exporter/collector"
+)
+
+const prefix = "junos_bgp_"
+
+var (
+	upDesc               *prometheus.Desc
+	flapsDesc            *prometheus.Desc
+	inputMessagesDesc    *prometheus.Desc
+	outputMessagesDesc   *prometheus.Desc
+	acceptedPrefixesDesc *prometheus.Desc
+	activePrefixesDesc   *prometheus.Desc
+	receivedPrefixesDesc *prometheus.Desc
+	rejectedPrefixesDesc *prometheus.Desc
+
+	ribAcceptedPrefixesDesc *prometheus.Desc
+	ribActivePrefixesDesc   *prometheus.Desc
+	ribReceivedPrefixesDesc *prometheus.Desc
+	ribRejectedPrefixesDesc *prometheus.Desc
+
+	peerStateDesc *prometheus.Desc
+	holdTimeDesc  *prometheus.Desc
+	keepaliveDesc *prometheus.Desc
+)
+
+// bgpPeerStates enumerates the FSM states `show bgp neighbor` reports.
+// junos_bgp_peer_state is emitted once per state per peer (1 for the
+// current state, 0 for the rest), the way kube-state-metrics exposes enums.
+var bgpPeerStates = []string{"Idle", "Connect", "Active", "OpenSent", "OpenConfirm", "Established"}
+
+func init() {
+	l := []string{"target", "peer", "asn"}
+	upDesc = prometheus.NewDesc(prefix+"up", "BGP session is established", l, nil)
+	flapsDesc = prometheus.NewDesc(prefix+"flaps", "Number of session flaps", l, nil)
+	inputMessagesDesc = prometheus.NewDesc(prefix+"input_messages", "Number of received messages", l, nil)
+	outputMessagesDesc = prometheus.NewDesc(prefix+"output_messages", "Number of sent messages", l, nil)
+	acceptedPrefixesDesc = prometheus.NewDesc(prefix+"accepted_prefixes", "Number of accepted prefixes across all address families", l, nil)
+	activePrefixesDesc = prometheus.NewDesc(prefix+"active_prefixes", "Number of active prefixes across all address families", l, nil)
+	receivedPrefixesDesc = prometheus.NewDesc(prefix+"received_prefixes", "Number of received prefixes across all address families", l, nil)
+	rejectedPrefixesDesc = prometheus.NewDesc(prefix+"rejected_prefixes", "Number of rejected prefixes across all address families", l, nil)
+
+	rl := []string{"target", "peer", "table", "family"}
+	ribAcceptedPrefixesDesc = prometheus.NewDesc(prefix+"rib_accepted_prefixes", "Number of accepted prefixes for a table/family", rl, nil)
+	ribActivePrefixesDesc = prometheus.NewDesc(prefix+"rib_active_prefixes", "Number of active prefixes for a table/family", rl, nil)
+	ribReceivedPrefixesDesc = prometheus.NewDesc(prefix+"rib_received_prefixes", "Number of received prefixes for a table/family", rl, nil)
+	ribRejectedPrefixesDesc = prometheus.NewDesc(prefix+"rib_rejected_prefixes", "Number of rejected prefixes for a table/family", rl, nil)
+
+	sl := []string{"target", "peer", "peer_group", "description", "state"}
+	peerStateDesc = prometheus.NewDesc(prefix+"peer_state", "BGP FSM state of the peer (1 for the current state, 0 for the rest)", sl, nil)
+
+	pl := []string{"target", "peer", "peer_group", "description"}
+	holdTimeDesc = prometheus.NewDesc(prefix+"hold_time_seconds", "Negotiated BGP hold time", pl, nil)
+	keepaliveDesc = prometheus.NewDesc(prefix+"keepalive_interval_seconds", "Negotiated BGP keepalive interval", pl, nil)
+
+	collector.Register("bgp", true, func() collector.Collector { return &BgpCollector{} })
+}
+
+// BgpCollector exposes both the summary-level metrics from `show bgp
+// summary` and the per-(peer, table, family) detail from `show bgp
+// neighbor`.
+type BgpCollector struct{}
+
+func (*BgpCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- upDesc
+	ch <- flapsDesc
+	ch <- inputMessagesDesc
+	ch <- outputMessagesDesc
+	ch <- acceptedPrefixesDesc
+	ch <- activePrefixesDesc
+	ch <- receivedPrefixesDesc
+	ch <- rejectedPrefixesDesc
+	ch <- ribAcceptedPrefixesDesc
+	ch <- ribActivePrefixesDesc
+	ch <- ribReceivedPrefixesDesc
+	ch <- ribRejectedPrefixesDesc
+	ch <- peerStateDesc
+	ch <- holdTimeDesc
+	ch <- keepaliveDesc
+}
+
+func (c *BgpCollector) Collect(datasource interface{}, ch chan<- prometheus.Metric, labelValues []string) error {
+	if ds, ok := datasource.(BgpSessionDatasource); ok {
+		if err := c.collectSessions(ds, ch, labelValues); err != nil {
+			return err
+		}
+	}
+
+	if ds, ok := datasource.(BgpNeighborDatasource); ok {
+		if err := c.collectNeighbors(ds, ch, labelValues); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (*BgpCollector) collectSessions(ds BgpSessionDatasource, ch chan<- prometheus.Metric, labelValues []string) error {
+	sessions, err := ds.BgpSessions()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		l := append(append([]string{}, labelValues...), s.Ip, strconv.Itoa(s.Asn))
+
+		up := 0.0
+		if s.Up {
+			up = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up, l...)
+		ch <- prometheus.MustNewConstMetric(flapsDesc, prometheus.GaugeValue, s.Flaps, l...)
+		ch <- prometheus.MustNewConstMetric(inputMessagesDesc, prometheus.GaugeValue, s.InputMessages, l...)
+		ch <- prometheus.MustNewConstMetric(outputMessagesDesc, prometheus.GaugeValue, s.OutputMessages, l...)
+		ch <- prometheus.MustNewConstMetric(acceptedPrefixesDesc, prometheus.GaugeValue, s.AcceptedPrefixes, l...)
+		ch <- prometheus.MustNewConstMetric(activePrefixesDesc, prometheus.GaugeValue, s.ActivePrefixes, l...)
+		ch <- prometheus.MustNewConstMetric(receivedPrefixesDesc, prometheus.GaugeValue, s.ReceivedPrefixes, l...)
+		ch <- prometheus.MustNewConstMetric(rejectedPrefixesDesc, prometheus.GaugeValue, s.RejectedPrefixes, l...)
+	}
+
+	return nil
+}
+
+func (*BgpCollector) collectNeighbors(ds BgpNeighborDatasource, ch chan<- prometheus.Metric, labelValues []string) error {
+	neighbors, err := ds.BgpNeighbors()
+	if err != nil {
+		return err
+	}
+
+	for _, n := range neighbors {
+		pl := append(append([]string{}, labelValues...), n.Ip, n.PeerGroup, n.Description)
+
+		ch <- prometheus.MustNewConstMetric(holdTimeDesc, prometheus.GaugeValue, n.HoldTime, pl...)
+		ch <- prometheus.MustNewConstMetric(keepaliveDesc, prometheus.GaugeValue, n.KeepaliveInterval, pl...)
+
+		for _, state := range bgpPeerStates {
+			v := 0.0
+			if state == n.State {
+				v = 1
+			}
+
+			sl := append(append([]string{}, pl...), state)
+			ch <- prometheus.MustNewConstMetric(peerStateDesc, prometheus.GaugeValue, v, sl...)
+		}
+
+		for _, rib := range n.Ribs {
+			rl := append(append([]string{}, labelValues...), n.Ip, rib.Table, rib.Family)
+
+			ch <- prometheus.MustNewConstMetric(ribAcceptedPrefixesDesc, prometheus.GaugeValue, rib.AcceptedPrefixes, rl...)
+			ch <- prometheus.MustNewConstMetric(ribActivePrefixesDesc, prometheus.GaugeValue, rib.ActivePrefixes, rl...)
+			ch <- prometheus.MustNewConstMetric(ribReceivedPrefixesDesc, prometheus.GaugeValue, rib.ReceivedPrefixes, rl...)
+			ch <- prometheus.MustNewConstMetric(ribRejectedPrefixesDesc, prometheus.GaugeValue, rib.RejectedPrefixes, rl...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package bgp
+
+// BgpSession is the per-peer summary produced by `show bgp summary`,
+// collapsing prefix counts across every address family the peer carries.
+type BgpSession struct {
+	Ip               string
+	Up               bool
+	Asn              int
+	Flaps            float64
+	InputMessages    float64
+	OutputMessages   float64
+	AcceptedPrefixes float64
+	ActivePrefixes   float64
+	ReceivedPrefixes float64
+	RejectedPrefixes float64
+}
+
+// BgpSessionDatasource is implemented by anything that can list the current
+// BGP sessions, typically *rpc.RpcClient.
+type BgpSessionDatasource interface {
+	BgpSessions() ([]*BgpSession, error)
+}
+
+// BgpRib is the prefix counts for one (table, family) a neighbor carries,
+// e.g. table "inet.0" of family "inet".
+type BgpRib struct {
+	Table            string
+	Family           string
+	AcceptedPrefixes float64
+	ActivePrefixes   float64
+	ReceivedPrefixes float64
+	RejectedPrefixes float64
+}
+
+// BgpNeighbor is the per-peer detail produced by `show bgp neighbor`,
+// carrying per-(table, family) prefix counts instead of BgpSession's
+// collapsed totals.
+type BgpNeighbor struct {
+	Ip                string
+	Asn               int
+	State             string
+	PeerGroup         string
+	Description       string
+	HoldTime          float64
+	KeepaliveInterval float64
+	Ribs              []*BgpRib
+}
+
+// BgpNeighborDatasource is implemented by anything that can list the
+// per-peer BGP neighbor detail, typically *rpc.RpcClient.
+type BgpNeighborDatasource interface {
+	BgpNeighbors() ([]*BgpNeighbor, error)
+}
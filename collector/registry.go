@@ -0,0 +1,86 @@
+// Package collector provides a pluggable registry for metric collectors.
+// Each metric domain package (interfaces, bgp, ospf, ...) registers itself
+// from an init() function, the same way node_exporter's collector/*
+// subpackages do, so a scrape can enable or disable individual collectors
+// without the packages needing to import one another.
+package collector
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is implemented by every metric domain package. datasource is
+// typically a *rpc.RpcClient, but is left as interface{} here so this
+// package does not have to import rpc, which would create an import cycle
+// (rpc already imports the per-domain packages that import collector).
+// Implementations type-assert datasource to the narrow interface they need.
+type Collector interface {
+	Describe(ch chan<- *prometheus.Desc)
+	Collect(datasource interface{}, ch chan<- prometheus.Metric, labelValues []string) error
+}
+
+type registration struct {
+	factory          func() Collector
+	enabledByDefault bool
+}
+
+var registry = make(map[string]*registration)
+
+// Register adds a Collector factory under name. enabledByDefault controls
+// whether it runs on a scrape that does not explicitly select collectors via
+// the collect[] query parameter.
+func Register(name string, enabledByDefault bool, factory func() Collector) {
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("collector: %q already registered", name))
+	}
+
+	registry[name] = &registration{factory: factory, enabledByDefault: enabledByDefault}
+}
+
+// Names returns the names of every registered collector, sorted.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Enabled instantiates the collectors that should run for a scrape given the
+// requested collector names (e.g. the collect[] query parameter values).
+// When requested is empty, every collector registered with
+// enabledByDefault=true runs.
+func Enabled(requested []string) map[string]Collector {
+	enabled := make(map[string]Collector)
+
+	if len(requested) == 0 {
+		for name, reg := range registry {
+			if reg.enabledByDefault {
+				enabled[name] = reg.factory()
+			}
+		}
+
+		return enabled
+	}
+
+	for _, name := range requested {
+		if reg, ok := registry[name]; ok {
+			enabled[name] = reg.factory()
+		}
+	}
+
+	return enabled
+}
+
+// RequestedFromQuery extracts the collect[] values from a /metrics query
+// string, e.g. "collect[]=bgp&collect[]=interfaces".
+func RequestedFromQuery(query url.Values) []string {
+	return query["collect[]"]
+}
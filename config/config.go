@@ -0,0 +1,52 @@
+// Package config loads the YAML module file used by the /probe endpoint to
+// look up per-target SSH settings.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/czerwonk/junos_exporter/connector"
+)
+
+// Module holds everything needed to connect to and scrape one kind of
+// device. A single exporter process can serve many targets as long as they
+// share a module.
+type Module struct {
+	User           string         `yaml:"user"`
+	KeyPath        string         `yaml:"ssh_key"`
+	KnownHostsPath string         `yaml:"known_hosts"`
+	Transport      connector.Kind `yaml:"transport"`
+	AlarmFilter    string         `yaml:"alarm_filter"`
+	Collectors     []string       `yaml:"collectors"`
+	Timeout        time.Duration  `yaml:"timeout"`
+}
+
+// Config is the top-level shape of the module file.
+type Config struct {
+	Modules map[string]*Module `yaml:"modules"`
+}
+
+// Load reads and parses the module file at path.
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %v", path, err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %v", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Module looks up a module by name.
+func (c *Config) Module(name string) (*Module, bool) {
+	m, ok := c.Modules[name]
+	return m, ok
+}
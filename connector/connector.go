@@ -0,0 +1,65 @@
+// Package connector provides the transports rpc.RpcClient uses to reach a
+// Junos device: an interactive CLI shell over SSH, or NETCONF over SSH for
+// devices where the CLI shell has been disabled.
+package connector
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Transport abstracts how a CLI command is executed against a device and how
+// its XML result is obtained, so rpc.RpcClient does not need to know whether
+// it is talking to an interactive shell or a NETCONF server.
+type Transport interface {
+	// Host returns the target device's host name or address, used for logging.
+	Host() string
+
+	// RunCommand executes cmd (e.g. "show bgp summary") and returns the raw
+	// XML produced for it.
+	RunCommand(cmd string) ([]byte, error)
+
+	// Close tears down the underlying connection.
+	Close() error
+}
+
+// Kind selects which Transport implementation to dial a device with.
+type Kind string
+
+const (
+	// CLI pipes "<cmd> | display xml" through an interactive SSH shell.
+	CLI Kind = "cli"
+
+	// NETCONF speaks RFC 6241 NETCONF over SSH, typically on port 830.
+	NETCONF Kind = "netconf"
+)
+
+// NewTransport dials host with the Transport selected by kind. It is the
+// entry point callers (e.g. a --transport flag or a per-device config value)
+// should use rather than constructing a Ssh/NetconfConnection directly.
+func NewTransport(kind Kind, host string, cfg *ssh.ClientConfig) (Transport, error) {
+	switch kind {
+	case "", CLI:
+		return NewSshConnection(host, cfg)
+	case NETCONF:
+		return NewNetconfConnection(host, cfg)
+	default:
+		return nil, fmt.Errorf("unknown transport %q", kind)
+	}
+}
+
+func dial(host, defaultPort string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, defaultPort)
+	}
+
+	client, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %v", host, err)
+	}
+
+	return client, nil
+}
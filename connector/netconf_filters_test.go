@@ -0,0 +1,20 @@
+package connector_test
+
+import (
+	"testing"
+
+	"github.com/czerwonk/junos_exporter/connector"
+	"github.com/czerwonk/junos_exporter/rpc"
+)
+
+// TestEveryCommandHasNetconfFilter guards against a command being added to
+// RpcClient without a matching NETCONF subtree filter, which would make it
+// fail with a "no netconf filter registered" error on every NETCONF-transport
+// target.
+func TestEveryCommandHasNetconfFilter(t *testing.T) {
+	for _, cmd := range rpc.Commands {
+		if !connector.SupportsNetconfFilter(cmd) {
+			t.Errorf("no netconf filter registered for %q", cmd)
+		}
+	}
+}
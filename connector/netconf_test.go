@@ -0,0 +1,121 @@
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+type nopWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestWriteChunked(t *testing.T) {
+	var buf bytes.Buffer
+	n := &NetconfConnection{stdin: nopWriteCloser{&buf}}
+
+	if err := n.writeChunked("hello"); err != nil {
+		t.Fatalf("writeChunked: %v", err)
+	}
+
+	want := "\n#5\nhello\n##\n"
+	if buf.String() != want {
+		t.Errorf("writeChunked wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestReadChunked(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "single chunk",
+			input: "\n#5\nhello\n##\n",
+			want:  "hello",
+		},
+		{
+			name:  "multiple chunks",
+			input: "\n#5\nhello\n#6\n world\n##\n",
+			want:  "hello world",
+		},
+		{
+			name:  "empty chunk stream",
+			input: "\n##\n",
+			want:  "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			n := &NetconfConnection{stdout: bufio.NewReader(strings.NewReader(c.input))}
+
+			got, err := n.readChunked()
+			if err != nil {
+				t.Fatalf("readChunked: %v", err)
+			}
+
+			if got != c.want {
+				t.Errorf("readChunked = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestReadChunkedInvalidHeader(t *testing.T) {
+	n := &NetconfConnection{stdout: bufio.NewReader(strings.NewReader("not-a-chunk-header\n"))}
+
+	if _, err := n.readChunked(); err == nil {
+		t.Fatal("expected an error for a malformed chunk header")
+	}
+}
+
+func TestDataFromRpcReply(t *testing.T) {
+	reply := `<rpc-reply message-id="1"><data><bgp-information><bgp-peer><peer-address>10.0.0.1</peer-address></bgp-peer></bgp-information></data></rpc-reply>`
+
+	data, err := dataFromRpcReply(reply)
+	if err != nil {
+		t.Fatalf("dataFromRpcReply: %v", err)
+	}
+
+	got := string(data)
+	if !strings.HasPrefix(got, "<data") || !strings.HasSuffix(got, "</data>") {
+		t.Errorf("dataFromRpcReply dropped the <data> wrapper: %s", got)
+	}
+
+	if !strings.Contains(got, "<bgp-information>") {
+		t.Errorf("dataFromRpcReply lost inner content: %s", got)
+	}
+}
+
+func TestRawElementBytesEscapesAttrs(t *testing.T) {
+	e := rawElement{
+		XMLName: xml.Name{Local: "data"},
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "note"}, Value: `a "quoted" & <tricky> value`}},
+		Inner:   []byte("<bgp-information/>"),
+	}
+
+	got := e.Bytes()
+
+	var roundTripped rawElement
+	if err := xml.Unmarshal(got, &roundTripped); err != nil {
+		t.Fatalf("Bytes() produced malformed XML: %v (data: %s)", err, got)
+	}
+
+	if len(roundTripped.Attrs) != 1 || roundTripped.Attrs[0].Value != e.Attrs[0].Value {
+		t.Errorf("attribute value did not round-trip: got %+v, want %q", roundTripped.Attrs, e.Attrs[0].Value)
+	}
+}
+
+func TestDataFromRpcReplyError(t *testing.T) {
+	reply := `<rpc-reply message-id="1"><rpc-error><error-message>no such rpc</error-message></rpc-error></rpc-reply>`
+
+	if _, err := dataFromRpcReply(reply); err == nil {
+		t.Fatal("expected an error for an rpc-error reply")
+	}
+}
@@ -0,0 +1,51 @@
+package connector
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SshConnection is the original Transport: it opens an interactive SSH shell
+// and pipes "<cmd> | display xml" through it.
+type SshConnection struct {
+	host   string
+	client *ssh.Client
+}
+
+// NewSshConnection dials host on port 22 (unless host already carries a
+// port) using cfg.
+func NewSshConnection(host string, cfg *ssh.ClientConfig) (*SshConnection, error) {
+	client, err := dial(host, "22", cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SshConnection{host: host, client: client}, nil
+}
+
+// Host returns the configured target host.
+func (s *SshConnection) Host() string {
+	return s.host
+}
+
+// Close tears down the underlying SSH connection.
+func (s *SshConnection) Close() error {
+	return s.client.Close()
+}
+
+// RunCommand pipes "cmd | display xml" through a fresh SSH session.
+func (s *SshConnection) RunCommand(cmd string) ([]byte, error) {
+	session, err := s.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("opening session to %s: %v", s.host, err)
+	}
+	defer session.Close()
+
+	out, err := session.Output(fmt.Sprintf("%s | display xml", cmd))
+	if err != nil {
+		return nil, fmt.Errorf("running %q on %s: %v", cmd, s.host, err)
+	}
+
+	return out, nil
+}
@@ -1,6 +1,12 @@
 package interfaces
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/czerwonk/junos_exporter/collector"
+)
 
 const prefix = "junos_interface_"
 
@@ -27,6 +33,8 @@ func init() {
 	adminStatusDesc = prometheus.NewDesc(prefix+"admin_up", "Admin operational status", l, nil)
 	operStatusDesc = prometheus.NewDesc(prefix+"up", "Interface operational status", l, nil)
 	errorStatusDesc = prometheus.NewDesc(prefix+"error_status", "Admin and operational status differ", l, nil)
+
+	collector.Register("interfaces", true, func() collector.Collector { return &InterfaceCollector{} })
 }
 
 type InterfaceCollector struct {
@@ -44,8 +52,13 @@ func (*InterfaceCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- errorStatusDesc
 }
 
-func (c *InterfaceCollector) Collect(datasource InterfaceStatsDatasource, ch chan<- prometheus.Metric, labelValues []string) error {
-	stats, err := datasource.InterfaceStats()
+func (c *InterfaceCollector) Collect(datasource interface{}, ch chan<- prometheus.Metric, labelValues []string) error {
+	ds, ok := datasource.(InterfaceStatsDatasource)
+	if !ok {
+		return fmt.Errorf("interfaces: datasource %T does not implement InterfaceStatsDatasource", datasource)
+	}
+
+	stats, err := ds.InterfaceStats()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,29 @@
+// Package logging builds the leveled logger shared across the exporter, so
+// scrape logs can be ingested by Loki/ELK alongside other network telemetry.
+package logging
+
+import (
+	"log/slog"
+	"os"
+)
+
+// New returns a logger writing to stderr in the requested format: "json" for
+// slog's JSON handler, anything else (including "logfmt") for its text
+// handler, which already produces logfmt-style output.
+func New(format string, debug bool) *slog.Logger {
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+
+	return slog.New(handler)
+}
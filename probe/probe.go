@@ -0,0 +1,193 @@
+// Package probe implements the /probe HTTP endpoint used to scrape a Junos
+// device on demand, following the Prometheus multi-target exporter pattern
+// (blackbox_exporter, mikrotik-exporter) so one process can monitor many
+// devices instead of one exporter per device.
+package probe
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/czerwonk/junos_exporter/collector"
+	"github.com/czerwonk/junos_exporter/config"
+	"github.com/czerwonk/junos_exporter/connector"
+	"github.com/czerwonk/junos_exporter/rpc"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Handler returns the http.HandlerFunc backing /probe. It looks up the
+// requested module in cfg, dials the requested target on demand, runs the
+// enabled collectors against it, and renders the result with a throwaway
+// prometheus.Registry.
+func Handler(cfg *config.Config) http.HandlerFunc {
+	stats := newStatsRegistry()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("target")
+		if target == "" {
+			http.Error(w, "target parameter is missing", http.StatusBadRequest)
+			return
+		}
+
+		moduleName := r.URL.Query().Get("module")
+		if moduleName == "" {
+			moduleName = "default"
+		}
+
+		module, ok := cfg.Module(moduleName)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown module %q", moduleName), http.StatusBadRequest)
+			return
+		}
+
+		reg := prometheus.NewRegistry()
+		successGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "junos_probe_success",
+			Help: "Whether the probe of the target succeeded",
+		})
+		durationGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "junos_probe_duration_seconds",
+			Help: "Duration of the probe in seconds",
+		})
+		reg.MustRegister(successGauge, durationGauge)
+
+		start := time.Now()
+		err := probe(reg, target, module, moduleName, stats, collector.RequestedFromQuery(r.URL.Query()))
+		durationGauge.Set(time.Since(start).Seconds())
+
+		if err != nil {
+			log.Printf("probe of %s (module %s) failed: %v", target, moduleName, err)
+			successGauge.Set(0)
+		} else {
+			successGauge.Set(1)
+		}
+
+		promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	}
+}
+
+// probe dials target, runs the collectors requested (falling back to the
+// module's configured collectors), and registers whatever metrics they
+// produced with reg. stats supplies the RpcClient's ExecutionStats, kept
+// alive across scrapes by the caller so its circuit breaker persists.
+func probe(reg *prometheus.Registry, target string, module *config.Module, moduleName string, stats *statsRegistry, requested []string) error {
+	auth, err := authMethod(module.KeyPath)
+	if err != nil {
+		return err
+	}
+
+	timeout := module.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	hostKeyCallback, err := hostKeyCallback(module.KnownHostsPath)
+	if err != nil {
+		return err
+	}
+
+	sshCfg := &ssh.ClientConfig{
+		User:            module.User,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         timeout,
+	}
+
+	conn, err := connector.NewTransport(module.Transport, target, sshCfg)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %v", target, err)
+	}
+	defer conn.Close()
+
+	client := rpc.NewClient(conn, slog.Default(), module.AlarmFilter)
+	client.SetStats(stats.forTarget(moduleName, target))
+
+	names := requested
+	if len(names) == 0 {
+		names = module.Collectors
+	}
+
+	labelValues := []string{target}
+	ch := make(chan prometheus.Metric, 64)
+	collected := make([]prometheus.Metric, 0)
+	done := make(chan struct{})
+
+	go func() {
+		for m := range ch {
+			collected = append(collected, m)
+		}
+		close(done)
+	}()
+
+	var firstErr error
+	for name, c := range collector.Enabled(names) {
+		if err := c.Collect(client, ch, labelValues); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("collector %s: %v", name, err)
+		}
+	}
+
+	close(ch)
+	<-done
+
+	reg.MustRegister(&constCollector{metrics: collected})
+	reg.MustRegister(client.Stats())
+
+	return firstErr
+}
+
+// hostKeyCallback builds a verifying ssh.HostKeyCallback from the OpenSSH
+// known_hosts file at path. Modules run privileged show/NETCONF commands
+// against production routers, so this deliberately has no insecure fallback:
+// a module without known_hosts configured fails to connect rather than
+// accepting any host key.
+func hostKeyCallback(knownHostsPath string) (ssh.HostKeyCallback, error) {
+	if knownHostsPath == "" {
+		return nil, fmt.Errorf("known_hosts file not configured for this module")
+	}
+
+	cb, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading known_hosts %s: %v", knownHostsPath, err)
+	}
+
+	return cb, nil
+}
+
+func authMethod(keyPath string) (ssh.AuthMethod, error) {
+	key, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading ssh key %s: %v", keyPath, err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ssh key %s: %v", keyPath, err)
+	}
+
+	return ssh.PublicKeys(signer), nil
+}
+
+// constCollector replays metrics gathered outside of a normal prometheus
+// Collect call (the junos collectors write to a plain channel, not a
+// registry) so they can be registered on the probe's throwaway registry.
+type constCollector struct {
+	metrics []prometheus.Metric
+}
+
+func (c *constCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *constCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range c.metrics {
+		ch <- m
+	}
+}
@@ -0,0 +1,61 @@
+package probe
+
+import (
+	"sync"
+	"time"
+
+	"github.com/czerwonk/junos_exporter/rpc"
+)
+
+// statsTTL bounds how long a target's ExecutionStats is kept after its last
+// scrape. Without it, statsRegistry would grow without bound against a
+// /probe endpoint fed a dynamic or rotating set of targets.
+const statsTTL = 1 * time.Hour
+
+type statsEntry struct {
+	stats    *rpc.ExecutionStats
+	lastUsed time.Time
+}
+
+// statsRegistry keeps one rpc.ExecutionStats per (module, target) alive for
+// the lifetime of the probe Handler. A new RpcClient is created for every
+// /probe request, so its circuit breaker and error counters would otherwise
+// reset on every scrape and could never accumulate the repeated failures
+// they are meant to short-circuit on. Entries untouched for statsTTL are
+// evicted so a dynamic set of targets doesn't leak memory forever.
+type statsRegistry struct {
+	mu   sync.Mutex
+	byID map[string]*statsEntry
+}
+
+func newStatsRegistry() *statsRegistry {
+	return &statsRegistry{byID: make(map[string]*statsEntry)}
+}
+
+func (r *statsRegistry) forTarget(module, target string) *rpc.ExecutionStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.evictLocked(now)
+
+	id := module + "/" + target
+
+	entry, ok := r.byID[id]
+	if !ok {
+		entry = &statsEntry{stats: rpc.NewExecutionStats()}
+		r.byID[id] = entry
+	}
+	entry.lastUsed = now
+
+	return entry.stats
+}
+
+// evictLocked removes entries not used within statsTTL. Callers must hold r.mu.
+func (r *statsRegistry) evictLocked(now time.Time) {
+	for id, entry := range r.byID {
+		if now.Sub(entry.lastUsed) > statsTTL {
+			delete(r.byID, id)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package probe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsRegistryReusesEntryWithinTTL(t *testing.T) {
+	r := newStatsRegistry()
+
+	first := r.forTarget("default", "10.0.0.1")
+	second := r.forTarget("default", "10.0.0.1")
+
+	if first != second {
+		t.Error("forTarget should return the same ExecutionStats for the same (module, target) within the TTL")
+	}
+}
+
+func TestStatsRegistryEvictsStaleEntries(t *testing.T) {
+	r := newStatsRegistry()
+
+	stale := r.forTarget("default", "10.0.0.1")
+	r.byID["default/10.0.0.1"].lastUsed = time.Now().Add(-2 * statsTTL)
+
+	fresh := r.forTarget("default", "10.0.0.1")
+
+	if stale == fresh {
+		t.Error("forTarget should allocate a new ExecutionStats once the old entry's TTL has expired")
+	}
+}
@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"strings"
+
+	"github.com/czerwonk/junos_exporter/bgp"
+)
+
+// BgpNeighborRpc is the response shape of `show bgp neighbor | display xml`.
+type BgpNeighborRpc struct {
+	Information struct {
+		Peers []BgpNeighborPeer `xml:"bgp-peer"`
+	} `xml:"bgp-information"`
+}
+
+type BgpNeighborPeer struct {
+	Ip                string           `xml:"peer-address"`
+	Asn               int              `xml:"peer-as"`
+	State             string           `xml:"peer-state"`
+	Description       string           `xml:"description"`
+	PeerGroup         string           `xml:"peer-group"`
+	HoldTime          float64          `xml:"bgp-option-information>hold-time"`
+	KeepaliveInterval float64          `xml:"bgp-option-information>keepalive-interval"`
+	Ribs              []BgpNeighborRib `xml:"bgp-rib"`
+}
+
+type BgpNeighborRib struct {
+	Name             string  `xml:"name"`
+	ActivePrefixes   float64 `xml:"active-prefix-count"`
+	ReceivedPrefixes float64 `xml:"received-prefix-count"`
+	AcceptedPrefixes float64 `xml:"accepted-prefix-count"`
+	RejectedPrefixes float64 `xml:"suppressed-prefix-count"`
+}
+
+// BgpNeighbors runs `show bgp neighbor` and returns per-(peer, table,
+// family) prefix counts, unlike BgpSessions which collapses them.
+func (c *RpcClient) BgpNeighbors() ([]*bgp.BgpNeighbor, error) {
+	var x = BgpNeighborRpc{}
+	err := c.runCommandAndParse("show bgp neighbor", &x)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbors := make([]*bgp.BgpNeighbor, 0)
+	for _, peer := range x.Information.Peers {
+		n := &bgp.BgpNeighbor{
+			Ip:                peer.Ip,
+			Asn:               peer.Asn,
+			State:             peer.State,
+			PeerGroup:         peer.PeerGroup,
+			Description:       peer.Description,
+			HoldTime:          peer.HoldTime,
+			KeepaliveInterval: peer.KeepaliveInterval,
+			Ribs:              make([]*bgp.BgpRib, 0, len(peer.Ribs)),
+		}
+
+		for _, rib := range peer.Ribs {
+			n.Ribs = append(n.Ribs, &bgp.BgpRib{
+				Table:            rib.Name,
+				Family:           familyForTable(rib.Name),
+				AcceptedPrefixes: rib.AcceptedPrefixes,
+				ActivePrefixes:   rib.ActivePrefixes,
+				ReceivedPrefixes: rib.ReceivedPrefixes,
+				RejectedPrefixes: rib.RejectedPrefixes,
+			})
+		}
+
+		neighbors = append(neighbors, n)
+	}
+
+	return neighbors, nil
+}
+
+// familyForTable derives the address family from a routing table name, e.g.
+// "inet6.0" -> "inet6".
+func familyForTable(table string) string {
+	if idx := strings.Index(table, "."); idx != -1 {
+		return table[:idx]
+	}
+
+	return table
+}
@@ -0,0 +1,20 @@
+package rpc
+
+// Commands lists every CLI command RpcClient can issue. Transports that only
+// support a fixed set of commands (e.g. the NETCONF subtree filter table in
+// package connector) can be tested against this list, so a command added
+// here without a matching filter entry fails a test instead of failing at
+// scrape time on every NETCONF target.
+var Commands = []string{
+	"show system alarms",
+	"show chassis alarms",
+	"show interfaces statistics detail",
+	"show bgp summary",
+	"show bgp neighbor",
+	"show ospf3 overview",
+	"show isis adjacency",
+	"show route summary",
+	"show chassis routing-engine",
+	"show chassis environment",
+	"show interfaces diagnostics optics",
+}
@@ -4,8 +4,9 @@ import (
 	"encoding/xml"
 	"fmt"
 	"strconv"
+	"time"
 
-	"log"
+	"log/slog"
 
 	"regexp"
 
@@ -22,13 +23,20 @@ import (
 )
 
 type RpcClient struct {
-	conn        *connector.SshConnection
-	debug       bool
+	conn        connector.Transport
+	logger      *slog.Logger
 	alarmFilter *regexp.Regexp
+	stats       *ExecutionStats
 }
 
-func NewClient(ssh *connector.SshConnection, debug bool, alarmFilter string) *RpcClient {
-	rpc := &RpcClient{conn: ssh, debug: debug, alarmFilter: nil}
+// NewClient creates an RpcClient talking to conn, logging per-RPC activity
+// to logger. A nil logger falls back to slog.Default().
+func NewClient(conn connector.Transport, logger *slog.Logger, alarmFilter string) *RpcClient {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	rpc := &RpcClient{conn: conn, logger: logger, alarmFilter: nil, stats: NewExecutionStats()}
 
 	if len(alarmFilter) > 0 {
 		rpc.alarmFilter = regexp.MustCompile(alarmFilter)
@@ -37,6 +45,22 @@ func NewClient(ssh *connector.SshConnection, debug bool, alarmFilter string) *Rp
 	return rpc
 }
 
+// Stats returns the RPC execution metrics (timings, errors, circuit breaker
+// state) collected so far, so callers can register them alongside the
+// metrics collectors that use this client as a datasource.
+func (c *RpcClient) Stats() *ExecutionStats {
+	return c.stats
+}
+
+// SetStats replaces the client's ExecutionStats. Callers that create a new
+// RpcClient per scrape (e.g. the /probe handler) but still want the circuit
+// breaker and error counters to accumulate across scrapes of the same
+// target should keep one ExecutionStats per target and inject it here
+// instead of relying on the one NewClient creates.
+func (c *RpcClient) SetStats(stats *ExecutionStats) {
+	c.stats = stats
+}
+
 func (c *RpcClient) AlarmCounter() (*alarm.AlarmCounter, error) {
 	red := 0
 	yellow := 0
@@ -313,20 +337,102 @@ func (c *RpcClient) InterfaceDiagnostics() ([]*interface_diagnostics.InterfaceDi
 	return diagnostics, nil
 }
 
+// runCommandAndParse runs cmd with a bounded timeout, retrying transient
+// failures with exponential backoff, and gives up early if the circuit
+// breaker for cmd is open because it has been failing repeatedly.
 func (c *RpcClient) runCommandAndParse(cmd string, obj interface{}) error {
-	if c.debug {
-		log.Printf("Running command on %s: %s\n", c.conn.Host, cmd)
+	breaker := c.stats.breakerFor(cmd)
+
+	if breaker.open() {
+		c.stats.circuitOpen.WithLabelValues(cmd).Set(1)
+		c.stats.errors.WithLabelValues(cmd, "circuit_open").Inc()
+		return fmt.Errorf("circuit breaker open for %q on %s", cmd, c.conn.Host())
 	}
+	c.stats.circuitOpen.WithLabelValues(cmd).Set(0)
+
+	backoff := initialRetryBackoff
+	var lastErr error
+
+	for attempt := 0; attempt <= maxRpcRetries; attempt++ {
+		lastErr = c.attemptCommand(cmd, obj)
+		if lastErr == nil {
+			breaker.recordSuccess()
+			return nil
+		}
+
+		if attempt < maxRpcRetries {
+			c.logger.Warn("command failed, retrying", "host", c.conn.Host(), "cmd", cmd, "attempt", attempt, "err", lastErr)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	breaker.recordFailure(circuitFailureThreshold, circuitCooldown)
+
+	return lastErr
+}
+
+// attemptCommand makes a single timed attempt at running cmd and parsing its
+// output into obj, recording its duration and any error reason.
+func (c *RpcClient) attemptCommand(cmd string, obj interface{}) error {
+	host := c.conn.Host()
+	start := time.Now()
+
+	c.logger.Debug("running command", "host", host, "cmd", cmd)
+
+	b, err := c.runWithTimeout(cmd, rpcTimeout)
+	c.stats.duration.WithLabelValues(cmd).Observe(time.Since(start).Seconds())
 
-	b, err := c.conn.RunCommand(fmt.Sprintf("%s | display xml", cmd))
 	if err != nil {
+		c.logger.Error("command failed", "host", host, "cmd", cmd, "err", err)
+		c.stats.errors.WithLabelValues(cmd, "transport_error").Inc()
 		return err
 	}
 
-	if c.debug {
-		log.Printf("Output for %s: %s\n", c.conn.Host, string(b))
-	}
+	c.logger.Debug("command completed", "host", host, "cmd", cmd, "duration", time.Since(start))
 
 	err = xml.Unmarshal(b, obj)
+	if err != nil {
+		c.logger.Warn("xml parse failed", "host", host, "cmd", cmd, "err", err, "output", snippet(b))
+		c.stats.errors.WithLabelValues(cmd, "parse_error").Inc()
+	}
+
 	return err
 }
+
+// runWithTimeout runs cmd on a goroutine and returns a timeout error if it
+// takes longer than timeout. connector.Transport has no context.Context
+// parameter, so the goroutine is left to finish in the background rather
+// than being cancelled; it updates nothing the caller still holds a
+// reference to.
+func (c *RpcClient) runWithTimeout(cmd string, timeout time.Duration) ([]byte, error) {
+	type result struct {
+		b   []byte
+		err error
+	}
+
+	ch := make(chan result, 1)
+	go func() {
+		b, err := c.conn.RunCommand(cmd)
+		ch <- result{b: b, err: err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.b, r.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out running %q on %s after %s", cmd, c.conn.Host(), timeout)
+	}
+}
+
+// snippet returns the first 200 bytes of b, for logging malformed XML
+// without flooding the log with a full `show ... | display xml` dump.
+func snippet(b []byte) string {
+	const max = 200
+
+	if len(b) <= max {
+		return string(b)
+	}
+
+	return string(b[:max]) + "..."
+}
@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// rpcTimeout bounds how long a single RPC attempt may take.
+	rpcTimeout = 10 * time.Second
+
+	// maxRpcRetries is the number of retries after the first attempt.
+	maxRpcRetries = 3
+
+	// initialRetryBackoff is doubled after each failed attempt.
+	initialRetryBackoff = 200 * time.Millisecond
+
+	// circuitFailureThreshold is the number of consecutive failures for a
+	// command that opens its circuit breaker.
+	circuitFailureThreshold = 5
+
+	// circuitCooldown is how long a command's circuit breaker stays open
+	// before the next scrape is allowed to try it again.
+	circuitCooldown = 30 * time.Second
+)
+
+// ExecutionStats tracks per-command RPC timing, errors, and circuit breaker
+// state for an RpcClient. It implements prometheus.Collector so it can be
+// registered next to the metrics collectors that use the client as a
+// datasource.
+type ExecutionStats struct {
+	duration    *prometheus.HistogramVec
+	errors      *prometheus.CounterVec
+	circuitOpen *prometheus.GaugeVec
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// NewExecutionStats creates an empty ExecutionStats.
+func NewExecutionStats() *ExecutionStats {
+	return &ExecutionStats{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "junos_rpc_duration_seconds",
+			Help: "Duration of a single RPC call",
+		}, []string{"cmd"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "junos_rpc_errors_total",
+			Help: "Number of RPC errors by reason",
+		}, []string{"cmd", "reason"}),
+		circuitOpen: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "junos_rpc_circuit_open",
+			Help: "Whether the circuit breaker for a command is currently open",
+		}, []string{"cmd"}),
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (s *ExecutionStats) Describe(ch chan<- *prometheus.Desc) {
+	s.duration.Describe(ch)
+	s.errors.Describe(ch)
+	s.circuitOpen.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (s *ExecutionStats) Collect(ch chan<- prometheus.Metric) {
+	s.duration.Collect(ch)
+	s.errors.Collect(ch)
+	s.circuitOpen.Collect(ch)
+}
+
+func (s *ExecutionStats) breakerFor(cmd string) *circuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.breakers[cmd]
+	if !ok {
+		b = &circuitBreaker{}
+		s.breakers[cmd] = b
+	}
+
+	return b
+}
+
+// circuitBreaker opens for a command once it has failed
+// circuitFailureThreshold times in a row, short-circuiting further attempts
+// until circuitCooldown has passed.
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	consecutiveFailures int
+	openUntil           time.Time
+}
+
+func (b *circuitBreaker) open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return time.Now().Before(b.openUntil)
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures = 0
+	b.openUntil = time.Time{}
+}
+
+func (b *circuitBreaker) recordFailure(threshold int, cooldown time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	if b.consecutiveFailures >= threshold {
+		b.openUntil = time.Now().Add(cooldown)
+	}
+}
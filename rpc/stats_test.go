@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerThreshold(t *testing.T) {
+	const threshold = 3
+	cooldown := 30 * time.Millisecond
+
+	cases := []struct {
+		name     string
+		failures int
+		wantOpen bool
+	}{
+		{"no failures", 0, false},
+		{"below threshold", threshold - 1, false},
+		{"at threshold", threshold, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := &circuitBreaker{}
+			for i := 0; i < c.failures; i++ {
+				b.recordFailure(threshold, cooldown)
+			}
+
+			if got := b.open(); got != c.wantOpen {
+				t.Errorf("open() = %v, want %v after %d failures", got, c.wantOpen, c.failures)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerClosesAfterCooldown(t *testing.T) {
+	const threshold = 2
+	cooldown := 20 * time.Millisecond
+
+	b := &circuitBreaker{}
+	for i := 0; i < threshold; i++ {
+		b.recordFailure(threshold, cooldown)
+	}
+
+	if !b.open() {
+		t.Fatal("breaker should be open right after reaching the threshold")
+	}
+
+	time.Sleep(cooldown + 10*time.Millisecond)
+
+	if b.open() {
+		t.Error("breaker should close again once the cooldown has elapsed")
+	}
+}
+
+func TestCircuitBreakerRecordSuccessResets(t *testing.T) {
+	const threshold = 2
+	cooldown := 30 * time.Millisecond
+
+	b := &circuitBreaker{}
+	for i := 0; i < threshold; i++ {
+		b.recordFailure(threshold, cooldown)
+	}
+
+	b.recordSuccess()
+	if b.open() {
+		t.Fatal("recordSuccess should close the breaker")
+	}
+
+	b.recordFailure(threshold, cooldown)
+	if b.open() {
+		t.Error("a single failure after recordSuccess should not reopen the breaker")
+	}
+}